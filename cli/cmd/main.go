@@ -6,14 +6,12 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"runtime"
 
 	"github.com/alecthomas/kong"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/log"
 	"github.com/jmgilman/pbl/cli/internal/run"
-	"github.com/jmgilman/pbl/cli/pkg/pkl"
 	"github.com/jmgilman/pbl/schema"
 	"github.com/posener/complete"
 	"github.com/willabides/kongplete"
@@ -33,6 +31,7 @@ var cli struct {
 	GlobalArgs
 
 	Version VersionCmd `cmd:"" help:"Print the version."`
+	Pkl     PklCmd     `cmd:"" help:"Manage local pkl binary installations."`
 
 	ShellCompletions kongplete.InstallCompletions `cmd:"" help:"Install shell completions"`
 }
@@ -106,70 +105,62 @@ func Run() int {
 	return 0
 }
 
-// getInstallPath returns the appropriate installation path for the pkl binary based on the OS
-func getInstallPath() (string, error) {
-	home, err := os.UserHomeDir()
+// checkPklBinary resolves the pkl binary to use, preferring a version
+// pinned or installed via the pbl pkl store, and falling back to PATH and
+// an interactive install prompt if none is found.
+// Returns the path to the pkl binary and any error that occurred.
+func checkPklBinary(logger *slog.Logger) (string, error) {
+	manager, err := newPklManager()
 	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+		return "", fmt.Errorf("failed to initialize pkl manager: %w", err)
 	}
 
-	binDir := filepath.Join(home, ".local", "bin")
-	if err := os.MkdirAll(binDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create .local/bin directory: %w", err)
+	if path, ok, err := manager.Current(); err != nil {
+		return "", fmt.Errorf("failed to resolve pinned pkl version: %w", err)
+	} else if ok {
+		logger.Debug("Using pinned pkl binary", "path", path)
+		return path, nil
 	}
 
-	binaryName := "pkl"
-	if runtime.GOOS == "windows" {
-		binaryName = "pkl.exe"
+	if path, err := manager.Resolve("latest"); err == nil {
+		logger.Debug("Using installed pkl binary", "path", path)
+		return path, nil
 	}
-	return filepath.Join(binDir, binaryName), nil
-}
 
-// checkPklBinary checks if the pkl binary is available in PATH and prompts for installation if not found.
-// Returns the path to the pkl binary and any error that occurred.
-func checkPklBinary(logger *slog.Logger) (string, error) {
 	pklPath, err := exec.LookPath("pkl")
+	if err == nil {
+		logger.Debug("Found pkl binary", "path", pklPath)
+		return pklPath, nil
+	}
+
+	logger.Error("pkl binary not found in PATH")
+
+	var shouldInstall bool
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("pkl binary not found in PATH").
+				Description("Would you like to install pkl now?").
+				Value(&shouldInstall),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return "", fmt.Errorf("failed to show installation prompt: %w", err)
+	}
+
+	if !shouldInstall {
+		return "", fmt.Errorf("pkl binary not found in PATH. Please install pkl first")
+	}
+
+	logger.Info("Downloading pkl...")
+	installPath, err := manager.Install("latest")
 	if err != nil {
-		logger.Error("pkl binary not found in PATH")
-
-		var shouldInstall bool
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewConfirm().
-					Title("pkl binary not found in PATH").
-					Description("Would you like to install pkl now?").
-					Value(&shouldInstall),
-			),
-		)
-
-		if err := form.Run(); err != nil {
-			return "", fmt.Errorf("failed to show installation prompt: %w", err)
-		}
-
-		if !shouldInstall {
-			return "", fmt.Errorf("pkl binary not found in PATH. Please install pkl first")
-		}
-
-		installPath, err := getInstallPath()
-		if err != nil {
-			return "", fmt.Errorf("failed to determine installation path: %w", err)
-		}
-
-		downloader := pkl.NewPklDownloader(
-			pkl.WithLogger(logger),
-		)
-
-		logger.Info("Downloading pkl...")
-		if err := downloader.Download(installPath); err != nil {
-			return "", fmt.Errorf("failed to download pkl: %w", err)
-		}
-
-		logger.Info("Successfully installed pkl", "path", installPath)
-		return installPath, nil
+		return "", fmt.Errorf("failed to download pkl: %w", err)
 	}
 
-	logger.Debug("Found pkl binary", "path", pklPath)
-	return pklPath, nil
+	logger.Info("Successfully installed pkl", "path", installPath)
+	return installPath, nil
 }
 
 func main() {
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jmgilman/pbl/cli/internal/run"
+	"github.com/jmgilman/pbl/cli/pkg/pkl"
+	"github.com/spf13/afero"
+)
+
+// PklCmd groups the subcommands for managing local pkl binary installations.
+type PklCmd struct {
+	Install    PklInstallCmd    `cmd:"" help:"Install a pkl version."`
+	List       PklListCmd       `cmd:"" help:"List installed pkl versions."`
+	ListRemote PklListRemoteCmd `cmd:"" name:"list-remote" help:"List pkl versions available for install."`
+	Use        PklUseCmd        `cmd:"" help:"Pin an installed pkl version as the active one."`
+	Uninstall  PklUninstallCmd  `cmd:"" help:"Remove an installed pkl version."`
+	Env        PklEnvCmd        `cmd:"" help:"Print shell exports for a resolved pkl binary."`
+
+	CheckUpdates PklCheckUpdatesCmd `cmd:"" name:"check-updates" help:"Check for a newer pkl release and optionally install it."`
+}
+
+// newPklManager builds a pkl.Manager rooted at the default store directory.
+// If PBL_PKL_MIRROR is set, releases are resolved from that HTTP mirror
+// before falling back to GitHub; PBL_PKL_VERSION_URL overrides the URL the
+// mirror's version index is fetched from.
+func newPklManager() (*pkl.Manager, error) {
+	root, err := pkl.DefaultStoreRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	fs := afero.NewOsFs()
+	downloaderOpts := []pkl.Option{pkl.WithFilesystem(fs)}
+
+	if cachePath, err := pkl.DefaultVersionCachePath(); err == nil {
+		downloaderOpts = append(downloaderOpts, pkl.WithVersionCache(fs, cachePath))
+	}
+
+	if mirror := os.Getenv("PBL_PKL_MIRROR"); mirror != "" {
+		httpClient := &http.Client{Timeout: 10 * time.Second}
+
+		var mirrorOpts []pkl.HTTPMirrorOption
+		if versionURL := os.Getenv("PBL_PKL_VERSION_URL"); versionURL != "" {
+			mirrorOpts = append(mirrorOpts, pkl.WithMirrorVersionURL(versionURL))
+		}
+
+		downloaderOpts = append(downloaderOpts, pkl.WithReleaseSource(
+			pkl.NewHTTPMirrorSource(httpClient, mirror, mirrorOpts...),
+			pkl.NewGitHubSource(httpClient),
+		))
+	}
+
+	manager := pkl.NewManager(fs, root, pkl.WithManagerDownloader(pkl.NewPklDownloader(downloaderOpts...)))
+	return manager, nil
+}
+
+// PklInstallCmd installs a pkl version into the local store.
+type PklInstallCmd struct {
+	Version string `arg:"" optional:"" default:"latest" help:"Version selector to install, e.g. 0.28.2, 0.28.x, or latest."`
+}
+
+func (c *PklInstallCmd) Run(ctx run.RunContext) error {
+	manager, err := newPklManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize pkl manager: %w", err)
+	}
+
+	path, err := manager.Install(c.Version)
+	if err != nil {
+		return fmt.Errorf("failed to install pkl %s: %w", c.Version, err)
+	}
+
+	fmt.Printf("Installed pkl to %s\n", path)
+	return nil
+}
+
+// PklListCmd lists the pkl versions installed in the local store.
+type PklListCmd struct{}
+
+func (c *PklListCmd) Run(ctx run.RunContext) error {
+	manager, err := newPklManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize pkl manager: %w", err)
+	}
+
+	items, err := manager.List()
+	if err != nil {
+		return fmt.Errorf("failed to list installed pkl versions: %w", err)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No pkl versions installed")
+		return nil
+	}
+
+	for _, item := range items {
+		fmt.Printf("%s\t%s\t%s\n", item.Version, item.Platform, item.Path)
+	}
+
+	return nil
+}
+
+// PklListRemoteCmd lists the pkl versions available for install from the
+// configured release sources.
+type PklListRemoteCmd struct{}
+
+func (c *PklListRemoteCmd) Run(ctx run.RunContext) error {
+	manager, err := newPklManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize pkl manager: %w", err)
+	}
+
+	versions, err := manager.ListRemote()
+	if err != nil {
+		return fmt.Errorf("failed to list remote pkl versions: %w", err)
+	}
+
+	for _, version := range versions {
+		fmt.Println(version)
+	}
+
+	return nil
+}
+
+// PklUseCmd pins an installed pkl version as the active one for the host
+// platform.
+type PklUseCmd struct {
+	Version string `arg:"" help:"Version selector to pin, e.g. 0.28.2 or 0.28.x."`
+}
+
+func (c *PklUseCmd) Run(ctx run.RunContext) error {
+	manager, err := newPklManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize pkl manager: %w", err)
+	}
+
+	path, err := manager.Use(c.Version)
+	if err != nil {
+		return fmt.Errorf("failed to use pkl %s: %w", c.Version, err)
+	}
+
+	fmt.Printf("Now using %s\n", path)
+	return nil
+}
+
+// PklUninstallCmd removes an installed pkl version from the local store.
+type PklUninstallCmd struct {
+	Selector string `arg:"" help:"Version or version-platform selector to remove, e.g. 0.28.2."`
+}
+
+func (c *PklUninstallCmd) Run(ctx run.RunContext) error {
+	manager, err := newPklManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize pkl manager: %w", err)
+	}
+
+	if err := manager.Uninstall(c.Selector); err != nil {
+		return fmt.Errorf("failed to uninstall pkl %s: %w", c.Selector, err)
+	}
+
+	fmt.Printf("Uninstalled pkl %s\n", c.Selector)
+	return nil
+}
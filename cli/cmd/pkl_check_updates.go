@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jmgilman/pbl/cli/internal/run"
+	"github.com/jmgilman/pbl/cli/pkg/pkl/versions"
+)
+
+// PklCheckUpdatesCmd force-refreshes the cached latest pkl release version
+// and reports whether a newer version is available than what's installed.
+type PklCheckUpdatesCmd struct {
+	Auto bool `help:"Automatically install the latest version if an update is available."`
+}
+
+func (c *PklCheckUpdatesCmd) Run(ctx run.RunContext) error {
+	manager, err := newPklManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize pkl manager: %w", err)
+	}
+
+	latest, installed, installedOK, err := manager.CheckLatest()
+	if err != nil {
+		return err
+	}
+
+	upToDate := installed == latest
+	if installedOK && !upToDate {
+		// An installed version at or above latest needs no update.
+		if sel, err := versions.Parse("latest"); err == nil {
+			highest, err := sel.Resolve([]string{latest, installed})
+			upToDate = err == nil && highest == installed
+		}
+	}
+
+	if installedOK && upToDate {
+		fmt.Printf("pkl %s is up to date\n", installed)
+		return nil
+	}
+
+	fmt.Printf("A newer pkl version is available: %s\n", latest)
+
+	if !c.Auto {
+		return nil
+	}
+
+	path, err := manager.Install(latest)
+	if err != nil {
+		return fmt.Errorf("failed to install pkl %s: %w", latest, err)
+	}
+
+	fmt.Printf("Installed pkl %s to %s\n", latest, path)
+	return nil
+}
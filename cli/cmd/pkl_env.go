@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jmgilman/pbl/cli/internal/run"
+	"github.com/jmgilman/pbl/cli/pkg/pkl/store"
+)
+
+// PklEnvCmd resolves a pkl binary from the local store and prints
+// shell-eval-able output for wiring it onto PATH, e.g.
+// `eval "$(pbl pkl env --version 0.28.x)"`.
+type PklEnvCmd struct {
+	Shell   string `enum:"bash,zsh,fish,powershell" default:"bash" help:"Shell syntax to emit exports in."`
+	Version string `default:"latest" help:"Version selector to resolve, e.g. 0.28.2, 0.28.x, or latest."`
+	Print   string `enum:"shell,path,json" default:"shell" help:"Output format: shell exports, bare path, or JSON."`
+}
+
+func (c *PklEnvCmd) Run(ctx run.RunContext) error {
+	manager, err := newPklManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize pkl manager: %w", err)
+	}
+
+	item, err := manager.ResolveItem(c.Version)
+	if err != nil {
+		return fmt.Errorf("failed to resolve pkl %s: %w", c.Version, err)
+	}
+
+	switch c.Print {
+	case "path":
+		fmt.Println(item.Path)
+		return nil
+	case "json":
+		return printPklEnvJSON(item)
+	default:
+		return printPklEnvShell(c.Shell, item.Path)
+	}
+}
+
+// pklEnvInfo is the structured output emitted by `pbl pkl env --print json`.
+type pklEnvInfo struct {
+	Version  string `json:"version"`
+	Path     string `json:"path"`
+	Platform string `json:"platform"`
+	Checksum string `json:"checksum"`
+}
+
+// printPklEnvJSON prints item as a pklEnvInfo JSON object.
+func printPklEnvJSON(item store.Item) error {
+	checksum, err := sha256File(item.Path)
+	if err != nil {
+		return fmt.Errorf("failed to checksum pkl binary: %w", err)
+	}
+
+	info := pklEnvInfo{
+		Version:  item.Version,
+		Path:     item.Path,
+		Platform: item.Platform,
+		Checksum: checksum,
+	}
+
+	encoded, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to encode pkl env info: %w", err)
+	}
+
+	fmt.Println(string(encoded))
+	return nil
+}
+
+// printPklEnvShell prints PATH/PKL_BIN exports in the syntax of shell.
+func printPklEnvShell(shell, path string) error {
+	dir := filepath.Dir(path)
+
+	switch shell {
+	case "bash", "zsh":
+		fmt.Printf("export PKL_BIN=%q\n", path)
+		fmt.Printf("export PATH=\"%s:$PATH\"\n", dir)
+	case "fish":
+		fmt.Printf("set -gx PKL_BIN %q\n", path)
+		fmt.Printf("set -gx PATH %q $PATH\n", dir)
+	case "powershell":
+		fmt.Printf("$env:PKL_BIN = %q\n", path)
+		fmt.Printf("$env:PATH = %q + \";\" + $env:PATH\n", dir)
+	default:
+		return fmt.Errorf("unsupported shell %q", shell)
+	}
+
+	return nil
+}
+
+// sha256File returns the hex-encoded SHA-256 digest of the file at path.
+func sha256File(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -1,15 +1,22 @@
 package pkl
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/spf13/afero"
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // openpgp is deprecated but still the package Apple's signatures verify against
 )
 
 // HTTPClient defines the interface for making HTTP requests
@@ -35,10 +42,18 @@ func (d *defaultRuntime) GOARCH() string {
 }
 
 type PklDownloader struct {
-	logger     *slog.Logger
-	httpClient HTTPClient
-	fs         afero.Fs
-	runtime    Runtime
+	logger           *slog.Logger
+	httpClient       HTTPClient
+	fs               afero.Fs
+	runtime          Runtime
+	expectedChecksum string
+	checksumURL      string
+	verifySignature  bool
+	signingKey       string
+	sources          []ReleaseSource
+	cacheFs          afero.Fs
+	cachePath        string
+	cacheTTL         time.Duration
 }
 
 // Option is a function that configures a PklDownloader
@@ -72,6 +87,52 @@ func WithRuntime(rt Runtime) Option {
 	}
 }
 
+// WithExpectedChecksum pins the SHA-256 checksum (hex-encoded) the
+// downloaded binary must match, bypassing the lookup of the release's
+// published "<asset>.sha256" file.
+func WithExpectedChecksum(checksum string) Option {
+	return func(d *PklDownloader) {
+		d.expectedChecksum = checksum
+	}
+}
+
+// WithChecksumURL overrides the URL the SHA-256 checksum is fetched from,
+// instead of deriving it from the release asset URL.
+func WithChecksumURL(url string) Option {
+	return func(d *PklDownloader) {
+		d.checksumURL = url
+	}
+}
+
+// WithSignatureVerification enables verifying the downloaded binary against
+// its published GPG signature. Apple does not currently publish a pkl
+// release-signing key, so this must be paired with WithSigningKey; enabling
+// it without one returns an error at verification time instead of silently
+// skipping the check.
+func WithSignatureVerification(enabled bool) Option {
+	return func(d *PklDownloader) {
+		d.verifySignature = enabled
+	}
+}
+
+// WithSigningKey sets the ASCII-armored public key used to verify release
+// signatures, required to use WithSignatureVerification until Apple
+// publishes an official pkl release-signing key.
+func WithSigningKey(armoredKey string) Option {
+	return func(d *PklDownloader) {
+		d.signingKey = armoredKey
+	}
+}
+
+// WithReleaseSource overrides the sources consulted to resolve pkl release
+// versions and download URLs, instead of the default GitHubSource. Sources
+// are tried in order, falling through to the next on error.
+func WithReleaseSource(sources ...ReleaseSource) Option {
+	return func(d *PklDownloader) {
+		d.sources = sources
+	}
+}
+
 // NewPklDownloader creates a new PklDownloader instance with the provided options
 func NewPklDownloader(opts ...Option) *PklDownloader {
 	d := &PklDownloader{
@@ -79,124 +140,279 @@ func NewPklDownloader(opts ...Option) *PklDownloader {
 		httpClient: &http.Client{Timeout: 10 * time.Second},
 		fs:         afero.NewOsFs(),
 		runtime:    &defaultRuntime{},
+		signingKey: appleSigningKey,
+		cacheTTL:   defaultVersionCacheTTL,
 	}
 
 	for _, opt := range opts {
 		opt(d)
 	}
 
+	if d.sources == nil {
+		d.sources = []ReleaseSource{NewGitHubSource(d.httpClient)}
+	}
+
 	return d
 }
 
 // Download fetches the latest version of Pkl and saves it to the specified path
 func (d *PklDownloader) Download(path string) error {
-	d.logger.Info("Starting Pkl download", "path", path)
-
 	version, err := d.getLatestPklVersion()
 	if err != nil {
 		return fmt.Errorf("failed to get latest version: %w", err)
 	}
 
-	downloadURL, err := d.getPklDownloadURL(version)
+	return d.DownloadVersion(version, path)
+}
+
+// DownloadVersion fetches the given Pkl release version, verifies its
+// SHA-256 checksum (and, if enabled, its GPG signature), and saves it to
+// the specified path. Each configured release source's asset URL is tried
+// in order, falling through to the next on fetch failure, so a mirror
+// outage or 404 doesn't prevent falling back to GitHub.
+func (d *PklDownloader) DownloadVersion(version, path string) error {
+	d.logger.Info("Starting Pkl download", "version", version, "path", path)
+
+	downloadURLs, err := d.assetURLs(version)
 	if err != nil {
 		return fmt.Errorf("failed to get download URL: %w", err)
 	}
 
-	d.logger.Debug("Downloading Pkl binary", "version", version, "url", downloadURL)
-	req, err := http.NewRequest("GET", downloadURL, nil)
+	var errs []error
+	for _, downloadURL := range downloadURLs {
+		if err := d.downloadFromURL(version, downloadURL, path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return nil
+	}
+
+	return errors.Join(errs...)
+}
+
+// downloadFromURL fetches the Pkl binary at downloadURL, verifies its
+// SHA-256 checksum (and, if enabled, its GPG signature), and saves it to
+// path.
+func (d *PklDownloader) downloadFromURL(version, downloadURL, path string) error {
+	expectedChecksum, err := d.resolveExpectedChecksum(downloadURL)
 	if err != nil {
-		return fmt.Errorf("failed to create download request: %w", err)
+		return fmt.Errorf("failed to get expected checksum: %w", err)
 	}
 
-	resp, err := d.httpClient.Do(req)
+	d.logger.Debug("Downloading Pkl binary", "version", version, "url", downloadURL)
+	body, err := d.fetch(downloadURL)
 	if err != nil {
 		return fmt.Errorf("failed to download binary: %w", err)
 	}
-	defer resp.Body.Close()
+	defer body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download binary: status code %d", resp.StatusCode)
+	hasher := sha256.New()
+	tee := io.TeeReader(body, hasher)
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, tee); err != nil {
+		return fmt.Errorf("failed to read binary: %w", err)
+	}
+
+	actualChecksum := hex.EncodeToString(hasher.Sum(nil))
+	if actualChecksum != expectedChecksum {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", expectedChecksum, actualChecksum)
+	}
+
+	if d.verifySignature {
+		if err := d.verifyDetachedSignature(downloadURL, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to verify signature: %w", err)
+		}
+	}
+
+	if err := d.writeBinary(path, &buf); err != nil {
+		// Clean up the partial file rather than leaving an unverified
+		// binary behind.
+		_ = d.fs.Remove(path)
+		return err
+	}
+
+	d.logger.Info("Successfully downloaded Pkl", "version", version, "path", path)
+	return nil
+}
+
+// writeBinary writes data to path, creating its parent directory if
+// necessary, and marks it executable.
+func (d *PklDownloader) writeBinary(path string, data io.Reader) error {
+	if err := d.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Create the file
 	out, err := d.fs.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
 	defer out.Close()
 
-	// Write the body to file
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
+	if _, err := io.Copy(out, data); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	// Make the file executable
 	if err := d.fs.Chmod(path, 0755); err != nil {
 		return fmt.Errorf("failed to make file executable: %w", err)
 	}
 
-	d.logger.Info("Successfully downloaded Pkl", "version", version, "path", path)
 	return nil
 }
 
-// getLatestPklVersion fetches the latest release version of Pkl from GitHub
-func (d *PklDownloader) getLatestPklVersion() (string, error) {
-	d.logger.Debug("Fetching latest Pkl version from GitHub")
-	req, err := http.NewRequest("GET", "https://api.github.com/repos/apple/pkl/releases/latest", nil)
+// resolveExpectedChecksum returns the SHA-256 checksum the downloaded
+// binary must match, either from an explicitly pinned checksum or by
+// fetching the release's published "<asset>.sha256" file.
+func (d *PklDownloader) resolveExpectedChecksum(downloadURL string) (string, error) {
+	if d.expectedChecksum != "" {
+		return strings.ToLower(strings.TrimSpace(d.expectedChecksum)), nil
+	}
+
+	checksumURL := d.checksumURL
+	if checksumURL == "" {
+		checksumURL = downloadURL + ".sha256"
+	}
+
+	body, err := d.fetch(checksumURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to fetch checksum file: %w", err)
 	}
+	defer body.Close()
 
-	// Add GitHub API version header
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read checksum file: %w", err)
+	}
 
-	resp, err := d.httpClient.Do(req)
+	// The published checksum file may be a bare digest or the usual
+	// "<digest>  <filename>" sha256sum format.
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("checksum file is empty")
+	}
+
+	return strings.ToLower(fields[0]), nil
+}
+
+// verifyDetachedSignature fetches the release's published "<asset>.asc"
+// detached GPG signature and verifies data against it using the configured
+// signing key.
+func (d *PklDownloader) verifyDetachedSignature(downloadURL string, data []byte) error {
+	if d.signingKey == "" {
+		return fmt.Errorf("no pkl signing key configured: pass one with WithSigningKey")
+	}
+
+	body, err := d.fetch(downloadURL + ".asc")
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch latest release: %w", err)
+		return fmt.Errorf("failed to fetch signature: %w", err)
 	}
-	defer resp.Body.Close()
+	defer body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch latest release: status code %d", resp.StatusCode)
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(d.signingKey))
+	if err != nil {
+		return fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	if _, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(data), body); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	return nil
+}
+
+// fetch issues a GET request for url and returns the response body, after
+// checking for a non-200 status code.
+func (d *PklDownloader) fetch(url string) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	var release struct {
-		TagName string `json:"tag_name"`
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
-	if err := json.Unmarshal(body, &release); err != nil {
-		return "", fmt.Errorf("failed to parse release data: %w", err)
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("status code %d", resp.StatusCode)
 	}
 
-	d.logger.Debug("Found latest Pkl version", "version", release.TagName)
-	return release.TagName, nil
+	return resp.Body, nil
 }
 
-// getPklDownloadURL returns the download URL for the latest Pkl release
-func (d *PklDownloader) getPklDownloadURL(version string) (string, error) {
-	baseURL := fmt.Sprintf("https://github.com/apple/pkl/releases/download/%s/", version)
+// ListVersions returns the Pkl release versions published by the first
+// configured release source that responds, trying each in order.
+func (d *PklDownloader) ListVersions() ([]string, error) {
+	d.logger.Debug("Fetching Pkl release versions")
+
+	var errs []error
+	for _, source := range d.sources {
+		versions, err := source.ListVersions(context.Background())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return versions, nil
+	}
 
-	downloadMap := map[string]string{
-		"darwin/amd64":  "pkl-macos-amd64",
-		"darwin/arm64":  "pkl-macos-aarch64", // Note: Go arm64 -> pkl aarch64
-		"linux/amd64":   "pkl-linux-amd64",
-		"linux/arm64":   "pkl-linux-aarch64", // Note: Go arm64 -> pkl aarch64
-		"windows/amd64": "pkl-windows-amd64.exe",
+	return nil, errors.Join(errs...)
+}
+
+// getLatestPklVersion resolves the latest Pkl release version, consulting
+// the version cache before falling through to the configured release
+// sources, tried in order.
+func (d *PklDownloader) getLatestPklVersion() (string, error) {
+	if version, ok := d.readVersionCache(); ok {
+		d.logger.Debug("Using cached latest Pkl version", "version", version)
+		return version, nil
+	}
+
+	return d.RefreshLatestVersion()
+}
+
+// RefreshLatestVersion resolves the latest Pkl release version from the
+// configured release sources, bypassing the version cache, and updates the
+// cache with the result.
+func (d *PklDownloader) RefreshLatestVersion() (string, error) {
+	d.logger.Debug("Fetching latest Pkl version")
+
+	var errs []error
+	for _, source := range d.sources {
+		version, err := source.LatestVersion(context.Background())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		d.logger.Debug("Found latest Pkl version", "version", version)
+		d.writeVersionCache(version)
+		return version, nil
 	}
 
-	goos := d.runtime.GOOS()
-	goarch := d.runtime.GOARCH()
-	lookupKey := fmt.Sprintf("%s/%s", goos, goarch)
+	return "", errors.Join(errs...)
+}
+
+// assetURLs returns the download URL for the given Pkl release version on
+// the current platform from every configured release source that supports
+// it, in source order, so DownloadVersion can fall through to the next
+// source if fetching from an earlier one fails.
+func (d *PklDownloader) assetURLs(version string) ([]string, error) {
+	platform := fmt.Sprintf("%s/%s", d.runtime.GOOS(), d.runtime.GOARCH())
+
+	var urls []string
+	var errs []error
+	for _, source := range d.sources {
+		url, err := source.AssetURL(version, platform)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		urls = append(urls, url)
+	}
 
-	filename, supported := downloadMap[lookupKey]
-	if !supported {
-		return "", fmt.Errorf("unsupported OS/architecture combination: %s/%s", goos, goarch)
+	if len(urls) == 0 {
+		return nil, errors.Join(errs...)
 	}
 
-	return baseURL + filename, nil
+	return urls, nil
 }
@@ -0,0 +1,255 @@
+package pkl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmgilman/pbl/cli/pkg/pkl/store"
+	"github.com/jmgilman/pbl/cli/pkg/pkl/versions"
+	"github.com/spf13/afero"
+)
+
+// currentFile is the name of the file within a Manager's store root that
+// records the version pinned by Use for the host platform.
+const currentFile = ".current"
+
+// errNoInstalledMatch indicates no installed version satisfied a selector,
+// as distinct from an error that prevented listing the store itself.
+var errNoInstalledMatch = errors.New("no installed version found")
+
+// Manager coordinates a Store of installed Pkl binaries with a Downloader
+// capable of fetching new ones, letting callers install, list, switch
+// between, and remove Pkl versions.
+type Manager struct {
+	downloader *PklDownloader
+	store      *store.Store
+	runtime    Runtime
+	fs         afero.Fs
+	root       string
+}
+
+// ManagerOption configures a Manager.
+type ManagerOption func(*Manager)
+
+// WithManagerDownloader sets the downloader used to fetch new Pkl versions.
+func WithManagerDownloader(d *PklDownloader) ManagerOption {
+	return func(m *Manager) {
+		m.downloader = d
+	}
+}
+
+// WithManagerRuntime sets the runtime used to determine the host platform.
+func WithManagerRuntime(rt Runtime) ManagerOption {
+	return func(m *Manager) {
+		m.runtime = rt
+	}
+}
+
+// NewManager creates a Manager backed by a Store rooted at root.
+func NewManager(fs afero.Fs, root string, opts ...ManagerOption) *Manager {
+	m := &Manager{
+		downloader: NewPklDownloader(WithFilesystem(fs)),
+		store:      store.New(fs, root),
+		runtime:    &defaultRuntime{},
+		fs:         fs,
+		root:       root,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// DefaultStoreRoot returns the OS-appropriate cache directory pbl uses to
+// store installed Pkl binaries, e.g. "~/.cache/pbl/pkl".
+func DefaultStoreRoot() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+
+	return filepath.Join(cacheDir, "pbl", "pkl"), nil
+}
+
+// platform returns the "<goos>/<goarch>" identifier for the host.
+func (m *Manager) platform() string {
+	return fmt.Sprintf("%s/%s", m.runtime.GOOS(), m.runtime.GOARCH())
+}
+
+// Install resolves selector against the versions available from the
+// downloader's release sources and downloads the match into the store, returning the path to the installed
+// binary. The literal selector "latest" is resolved through the
+// downloader's cached latest-version lookup rather than listing every
+// published release.
+func (m *Manager) Install(selector string) (string, error) {
+	sel, err := versions.Parse(selector)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse version selector: %w", err)
+	}
+
+	version, err := m.resolveRemoteVersion(selector, sel)
+	if err != nil {
+		return "", err
+	}
+
+	path := m.store.Path(version, m.platform())
+	if err := m.downloader.DownloadVersion(version, path); err != nil {
+		return "", fmt.Errorf("failed to download pkl %s: %w", version, err)
+	}
+
+	return path, nil
+}
+
+// resolveRemoteVersion resolves sel to a concrete release version. The
+// literal selector "latest" consults the downloader's cached latest-version
+// lookup; any other selector is resolved against the full list of
+// published versions.
+func (m *Manager) resolveRemoteVersion(selector string, sel versions.Selector) (string, error) {
+	if strings.EqualFold(strings.TrimSpace(selector), "latest") {
+		version, err := m.downloader.getLatestPklVersion()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve latest pkl version: %w", err)
+		}
+		return version, nil
+	}
+
+	remote, err := m.downloader.ListVersions()
+	if err != nil {
+		return "", fmt.Errorf("failed to list remote versions: %w", err)
+	}
+
+	version, err := sel.Resolve(remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve version selector %q: %w", selector, err)
+	}
+
+	return version, nil
+}
+
+// List returns every Pkl version currently installed in the store.
+func (m *Manager) List() ([]store.Item, error) {
+	return m.store.List()
+}
+
+// ListRemote returns the Pkl versions available from the downloader's
+// release sources.
+func (m *Manager) ListRemote() ([]string, error) {
+	return m.downloader.ListVersions()
+}
+
+// Resolve finds the highest installed version matching selector for the
+// host platform and returns the path to its binary.
+func (m *Manager) Resolve(selector string) (string, error) {
+	path, _, err := m.resolveInstalled(selector)
+	return path, err
+}
+
+// ResolveItem finds the highest installed version matching selector for the
+// host platform and returns its store.Item.
+func (m *Manager) ResolveItem(selector string) (store.Item, error) {
+	path, version, err := m.resolveInstalled(selector)
+	if err != nil {
+		return store.Item{}, err
+	}
+
+	return store.Item{Version: version, Platform: m.platform(), Path: path}, nil
+}
+
+// Use pins the highest installed version matching selector as the active
+// Pkl version for the host platform and returns the path to its binary.
+// The resolved version is pinned, not the selector itself. The pin is consulted by Current on
+// subsequent invocations.
+func (m *Manager) Use(selector string) (string, error) {
+	path, version, err := m.resolveInstalled(selector)
+	if err != nil {
+		return "", err
+	}
+
+	if err := afero.WriteFile(m.fs, filepath.Join(m.root, currentFile), []byte(version), 0644); err != nil {
+		return "", fmt.Errorf("failed to pin pkl version: %w", err)
+	}
+
+	return path, nil
+}
+
+// Current returns the path to the version pinned by Use, if any. A pin left
+// behind by Use that no longer resolves to an installed version (e.g. it
+// was since uninstalled) is treated the same as no pin at all.
+func (m *Manager) Current() (path string, ok bool, err error) {
+	data, err := afero.ReadFile(m.fs, filepath.Join(m.root, currentFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to read pinned pkl version: %w", err)
+	}
+
+	path, _, err = m.resolveInstalled(strings.TrimSpace(string(data)))
+	if err != nil {
+		if errors.Is(err, errNoInstalledMatch) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	return path, true, nil
+}
+
+// CheckLatest force-refreshes the cached latest Pkl release version and
+// reports it alongside the highest version currently installed for the
+// host platform, if any.
+func (m *Manager) CheckLatest() (latest string, installed string, installedOK bool, err error) {
+	latest, err = m.downloader.RefreshLatestVersion()
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to refresh latest pkl version: %w", err)
+	}
+
+	item, err := m.ResolveItem("latest")
+	if err != nil {
+		return latest, "", false, nil
+	}
+
+	return latest, item.Version, true, nil
+}
+
+// Uninstall removes the store entries matching selector.
+func (m *Manager) Uninstall(selector string) error {
+	return m.store.Remove(selector)
+}
+
+// resolveInstalled finds the highest installed version matching selector
+// for the host platform and returns its path and version string.
+func (m *Manager) resolveInstalled(selector string) (path, version string, err error) {
+	sel, err := versions.Parse(selector)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse version selector: %w", err)
+	}
+
+	items, err := m.store.List()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list installed versions: %w", err)
+	}
+
+	platform := m.platform()
+	paths := make(map[string]string, len(items))
+	var candidates []string
+	for _, item := range items {
+		if item.Platform != platform {
+			continue
+		}
+		candidates = append(candidates, item.Version)
+		paths[item.Version] = item.Path
+	}
+
+	version, err = sel.Resolve(candidates)
+	if err != nil {
+		return "", "", fmt.Errorf("%w matching %q", errNoInstalledMatch, selector)
+	}
+
+	return paths[version], version, nil
+}
@@ -0,0 +1,190 @@
+package pkl
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_UseAndCurrent(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs, "/cache/pbl/pkl", WithManagerRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}))
+
+	_, err := mgr.store.Add("0.28.2", "linux/amd64", strings.NewReader("binary"))
+	require.NoError(t, err)
+
+	_, ok, err := mgr.Current()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	path, err := mgr.Use("0.28.x")
+	require.NoError(t, err)
+	require.Equal(t, mgr.store.Path("0.28.2", "linux/amd64"), path)
+
+	current, ok, err := mgr.Current()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, path, current)
+}
+
+func TestManager_ResolveItem(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs, "/cache/pbl/pkl", WithManagerRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}))
+
+	_, err := mgr.store.Add("0.28.2", "linux/amd64", strings.NewReader("binary"))
+	require.NoError(t, err)
+
+	item, err := mgr.ResolveItem("0.28.x")
+	require.NoError(t, err)
+	require.Equal(t, "0.28.2", item.Version)
+	require.Equal(t, "linux/amd64", item.Platform)
+	require.Equal(t, mgr.store.Path("0.28.2", "linux/amd64"), item.Path)
+}
+
+func TestManager_ResolveNoMatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs, "/cache/pbl/pkl", WithManagerRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}))
+
+	_, err := mgr.Resolve("0.28.x")
+	require.Error(t, err)
+}
+
+func TestManager_Install(t *testing.T) {
+	// Regression test: the store root has never been created, reproducing
+	// Install on a fresh machine where store.Add would otherwise be the
+	// only MkdirAll-ing code path.
+	root := filepath.Join(t.TempDir(), "pbl", "pkl")
+
+	mockClient := NewMockHTTPClient(t)
+	binary := []byte("pkl binary contents")
+
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://api.github.com/repos/apple/pkl/releases?per_page=100&page=1"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`[{"tag_name": "0.28.2"}]`)),
+		}, nil)
+
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://github.com/apple/pkl/releases/download/0.28.2/pkl-linux-amd64.sha256"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(checksumOf(binary))),
+		}, nil)
+
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://github.com/apple/pkl/releases/download/0.28.2/pkl-linux-amd64"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(binary)),
+		}, nil)
+
+	downloader := NewPklDownloader(
+		WithHTTPClient(mockClient),
+		WithFilesystem(afero.NewOsFs()),
+		WithRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}),
+	)
+	mgr := NewManager(afero.NewOsFs(), root,
+		WithManagerDownloader(downloader),
+		WithManagerRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}))
+
+	path, err := mgr.Install("0.28.x")
+	require.NoError(t, err)
+	require.Equal(t, mgr.store.Path("0.28.2", "linux/amd64"), path)
+
+	data, err := afero.ReadFile(afero.NewOsFs(), path)
+	require.NoError(t, err)
+	require.Equal(t, binary, data)
+}
+
+func TestManager_Install_LatestUsesVersionCache(t *testing.T) {
+	cacheFs := afero.NewMemMapFs()
+	cachePath := "/cache/pbl/pkl-versions.json"
+	writeVersionCacheFixture(t, cacheFs, cachePath, "0.28.2", time.Now())
+
+	root := filepath.Join(t.TempDir(), "pbl", "pkl")
+	binary := []byte("pkl binary contents")
+
+	// No expectation is set for the releases-listing endpoint: a fresh
+	// cache entry must let Install("latest") skip it entirely.
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://github.com/apple/pkl/releases/download/0.28.2/pkl-linux-amd64.sha256"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(checksumOf(binary))),
+		}, nil)
+
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://github.com/apple/pkl/releases/download/0.28.2/pkl-linux-amd64"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(binary)),
+		}, nil)
+
+	downloader := NewPklDownloader(
+		WithHTTPClient(mockClient),
+		WithFilesystem(afero.NewOsFs()),
+		WithRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}),
+		WithVersionCache(cacheFs, cachePath),
+	)
+	mgr := NewManager(afero.NewOsFs(), root,
+		WithManagerDownloader(downloader),
+		WithManagerRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}))
+
+	path, err := mgr.Install("latest")
+	require.NoError(t, err)
+	require.Equal(t, mgr.store.Path("0.28.2", "linux/amd64"), path)
+}
+
+func TestManager_Uninstall(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs, "/cache/pbl/pkl", WithManagerRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}))
+
+	_, err := mgr.store.Add("0.28.2", "linux/amd64", strings.NewReader("binary"))
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.Uninstall("0.28.2"))
+
+	items, err := mgr.List()
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestManager_UninstallPinnedVersion(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs, "/cache/pbl/pkl", WithManagerRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}))
+
+	_, err := mgr.store.Add("0.28.2", "linux/amd64", strings.NewReader("binary"))
+	require.NoError(t, err)
+
+	_, err = mgr.Use("0.28.2")
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.Uninstall("0.28.2"))
+
+	// The dangling pin left behind by Use must not break subsequent
+	// resolution; Current should report "not pinned" rather than erroring.
+	path, ok, err := mgr.Current()
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Empty(t, path)
+}
@@ -0,0 +1,286 @@
+package pkl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jmgilman/pbl/cli/pkg/pkl/versions"
+)
+
+// ReleaseSource resolves pkl release metadata: the latest published
+// version, every published version, and the download URL for a given
+// version and platform.
+type ReleaseSource interface {
+	// LatestVersion returns the most recently published release version.
+	LatestVersion(ctx context.Context) (string, error)
+	// ListVersions returns every published release version.
+	ListVersions(ctx context.Context) ([]string, error)
+	// AssetURL returns the download URL for the release asset matching
+	// version and platform (a "<goos>/<goarch>" pair).
+	AssetURL(version, platform string) (string, error)
+}
+
+// platformAssets maps a "<goos>/<goarch>" pair to the pkl release asset
+// filename published for it.
+var platformAssets = map[string]string{
+	"darwin/amd64":  "pkl-macos-amd64",
+	"darwin/arm64":  "pkl-macos-aarch64", // Note: Go arm64 -> pkl aarch64
+	"linux/amd64":   "pkl-linux-amd64",
+	"linux/arm64":   "pkl-linux-aarch64", // Note: Go arm64 -> pkl aarch64
+	"windows/amd64": "pkl-windows-amd64.exe",
+}
+
+// assetName returns the release asset filename for platform, or an error
+// if the platform is unsupported.
+func assetName(platform string) (string, error) {
+	filename, ok := platformAssets[platform]
+	if !ok {
+		return "", fmt.Errorf("unsupported OS/architecture combination: %s", platform)
+	}
+	return filename, nil
+}
+
+// GitHubSource resolves pkl releases from the apple/pkl GitHub repository.
+type GitHubSource struct {
+	httpClient HTTPClient
+	token      string
+}
+
+// GitHubSourceOption configures a GitHubSource.
+type GitHubSourceOption func(*GitHubSource)
+
+// WithGitHubToken authenticates GitHub API requests with token, raising
+// the unauthenticated rate limit of 60 requests/hour.
+func WithGitHubToken(token string) GitHubSourceOption {
+	return func(s *GitHubSource) {
+		s.token = token
+	}
+}
+
+// NewGitHubSource creates a GitHubSource backed by httpClient. The token
+// defaults to the GITHUB_TOKEN environment variable.
+func NewGitHubSource(httpClient HTTPClient, opts ...GitHubSourceOption) *GitHubSource {
+	s := &GitHubSource{
+		httpClient: httpClient,
+		token:      os.Getenv("GITHUB_TOKEN"),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// newRequest builds a GitHub API GET request, attaching auth if configured.
+func (s *GitHubSource) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	return req, nil
+}
+
+// LatestVersion fetches the latest published release tag.
+func (s *GitHubSource) LatestVersion(ctx context.Context) (string, error) {
+	req, err := s.newRequest(ctx, "https://api.github.com/repos/apple/pkl/releases/latest")
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch latest release: status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal(body, &release); err != nil {
+		return "", fmt.Errorf("failed to parse release data: %w", err)
+	}
+
+	return release.TagName, nil
+}
+
+// ListVersions fetches every published release tag, paginating through the
+// GitHub releases API 100 results at a time.
+func (s *GitHubSource) ListVersions(ctx context.Context) ([]string, error) {
+	var versions []string
+
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/apple/pkl/releases?per_page=100&page=%d", page)
+		req, err := s.newRequest(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+
+		releases, err := s.fetchReleasesPage(req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, release := range releases {
+			versions = append(versions, release.TagName)
+		}
+
+		if len(releases) < 100 {
+			break
+		}
+	}
+
+	return versions, nil
+}
+
+// fetchReleasesPage issues req and decodes a single page of releases.
+func (s *GitHubSource) fetchReleasesPage(req *http.Request) ([]struct {
+	TagName string `json:"tag_name"`
+}, error) {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch releases: status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var releases []struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse release data: %w", err)
+	}
+
+	return releases, nil
+}
+
+// AssetURL returns the download URL for the pkl release asset matching
+// version and platform.
+func (s *GitHubSource) AssetURL(version, platform string) (string, error) {
+	filename, err := assetName(platform)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("https://github.com/apple/pkl/releases/download/%s/%s", version, filename), nil
+}
+
+// HTTPMirrorSource resolves pkl releases from a directory index served by
+// an internal mirror, for air-gapped or rate-limit-constrained
+// environments. It expects a "<baseURL>/<version>/<asset>" layout and a
+// JSON array of version strings at its version index URL, mirroring the
+// split controller-runtime makes between its GitHub and GCS release
+// sources.
+type HTTPMirrorSource struct {
+	httpClient HTTPClient
+	baseURL    string
+	versionURL string
+}
+
+// HTTPMirrorOption configures an HTTPMirrorSource.
+type HTTPMirrorOption func(*HTTPMirrorSource)
+
+// WithMirrorVersionURL overrides the URL the version index is fetched
+// from, instead of "<baseURL>/versions.json".
+func WithMirrorVersionURL(url string) HTTPMirrorOption {
+	return func(s *HTTPMirrorSource) {
+		s.versionURL = url
+	}
+}
+
+// NewHTTPMirrorSource creates an HTTPMirrorSource rooted at baseURL.
+func NewHTTPMirrorSource(httpClient HTTPClient, baseURL string, opts ...HTTPMirrorOption) *HTTPMirrorSource {
+	s := &HTTPMirrorSource{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.versionURL == "" {
+		s.versionURL = s.baseURL + "/versions.json"
+	}
+
+	return s
+}
+
+// ListVersions fetches the mirror's version index.
+func (s *HTTPMirrorSource) ListVersions(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.versionURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch version index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch version index: status code %d", resp.StatusCode)
+	}
+
+	var versions []string
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("failed to parse version index: %w", err)
+	}
+
+	return versions, nil
+}
+
+// LatestVersion returns the highest version in the mirror's version index.
+func (s *HTTPMirrorSource) LatestVersion(ctx context.Context) (string, error) {
+	all, err := s.ListVersions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sel, err := versions.Parse("latest")
+	if err != nil {
+		return "", err
+	}
+
+	return sel.Resolve(all)
+}
+
+// AssetURL returns the download URL for the pkl release asset matching
+// version and platform, served at "<baseURL>/<version>/<asset>".
+func (s *HTTPMirrorSource) AssetURL(version, platform string) (string, error) {
+	filename, err := assetName(platform)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s/%s", s.baseURL, version, filename), nil
+}
@@ -0,0 +1,240 @@
+package pkl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubSource_LatestVersion(t *testing.T) {
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://api.github.com/repos/apple/pkl/releases/latest" &&
+				req.Header.Get("Authorization") == ""
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"tag_name": "0.28.2"}`))),
+		}, nil)
+
+	source := NewGitHubSource(mockClient)
+	version, err := source.LatestVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "0.28.2", version)
+}
+
+func TestGitHubSource_LatestVersion_Error(t *testing.T) {
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.Anything).
+		Return(nil, fmt.Errorf("connection refused"))
+
+	source := NewGitHubSource(mockClient)
+	_, err := source.LatestVersion(context.Background())
+	assert.EqualError(t, err, "failed to fetch latest release: connection refused")
+}
+
+func TestGitHubSource_WithToken(t *testing.T) {
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.Header.Get("Authorization") == "Bearer test-token"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"tag_name": "0.28.2"}`))),
+		}, nil)
+
+	source := NewGitHubSource(mockClient, WithGitHubToken("test-token"))
+	_, err := source.LatestVersion(context.Background())
+	require.NoError(t, err)
+}
+
+func TestGitHubSource_ListVersions_Paginates(t *testing.T) {
+	mockClient := NewMockHTTPClient(t)
+	page1 := make([]byte, 0)
+	page1 = append(page1, '[')
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			page1 = append(page1, ',')
+		}
+		page1 = append(page1, []byte(fmt.Sprintf(`{"tag_name": "0.%d.0"}`, i))...)
+	}
+	page1 = append(page1, ']')
+
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://api.github.com/repos/apple/pkl/releases?per_page=100&page=1"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(page1)),
+		}, nil)
+
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://api.github.com/repos/apple/pkl/releases?per_page=100&page=2"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`[{"tag_name": "0.100.0"}]`))),
+		}, nil)
+
+	source := NewGitHubSource(mockClient)
+	versions, err := source.ListVersions(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, versions, 101)
+	assert.Equal(t, "0.100.0", versions[100])
+}
+
+func TestGitHubSource_AssetURL(t *testing.T) {
+	source := NewGitHubSource(NewMockHTTPClient(t))
+	url, err := source.AssetURL("0.28.2", "linux/amd64")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/apple/pkl/releases/download/0.28.2/pkl-linux-amd64", url)
+
+	_, err = source.AssetURL("0.28.2", "unsupported/unsupported")
+	assert.EqualError(t, err, "unsupported OS/architecture combination: unsupported/unsupported")
+}
+
+func TestHTTPMirrorSource_ListVersions(t *testing.T) {
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://mirror.example.com/pkl/versions.json"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`["0.28.0", "0.28.2", "0.28.1"]`))),
+		}, nil)
+
+	source := NewHTTPMirrorSource(mockClient, "https://mirror.example.com/pkl/")
+	versions, err := source.ListVersions(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.28.0", "0.28.2", "0.28.1"}, versions)
+}
+
+func TestHTTPMirrorSource_LatestVersion(t *testing.T) {
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.Anything).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`["0.28.0", "0.28.2", "0.28.1"]`))),
+		}, nil)
+
+	source := NewHTTPMirrorSource(mockClient, "https://mirror.example.com/pkl")
+	version, err := source.LatestVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "0.28.2", version)
+}
+
+func TestHTTPMirrorSource_WithVersionURL(t *testing.T) {
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://mirror.example.com/index.json"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`["0.28.2"]`))),
+		}, nil)
+
+	source := NewHTTPMirrorSource(mockClient, "https://mirror.example.com/pkl", WithMirrorVersionURL("https://mirror.example.com/index.json"))
+	_, err := source.ListVersions(context.Background())
+	require.NoError(t, err)
+}
+
+func TestHTTPMirrorSource_AssetURL(t *testing.T) {
+	source := NewHTTPMirrorSource(NewMockHTTPClient(t), "https://mirror.example.com/pkl/")
+	url, err := source.AssetURL("0.28.2", "darwin/arm64")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mirror.example.com/pkl/0.28.2/pkl-macos-aarch64", url)
+}
+
+func TestPklDownloader_WithReleaseSource_FallsThrough(t *testing.T) {
+	failingClient := NewMockHTTPClient(t)
+	failingClient.EXPECT().
+		Do(mock.Anything).
+		Return(nil, fmt.Errorf("connection refused"))
+
+	workingClient := NewMockHTTPClient(t)
+	workingClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://mirror.example.com/pkl/versions.json"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`["0.28.2"]`))),
+		}, nil)
+
+	downloader := NewPklDownloader(WithReleaseSource(
+		NewGitHubSource(failingClient),
+		NewHTTPMirrorSource(workingClient, "https://mirror.example.com/pkl"),
+	))
+
+	versions, err := downloader.ListVersions()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.28.2"}, versions)
+}
+
+func TestPklDownloader_DownloadVersion_FallsThroughOnFetchFailure(t *testing.T) {
+	binary := []byte("pkl binary contents")
+
+	// DownloadVersion fetches checksums and binaries through the
+	// downloader's own HTTP client rather than a source's, so one mock
+	// client must stand in for both the mirror and GitHub endpoints.
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://mirror.example.com/pkl/0.28.2/pkl-linux-amd64.sha256"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(bytes.NewReader([]byte("Not Found"))),
+		}, nil)
+
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://github.com/apple/pkl/releases/download/0.28.2/pkl-linux-amd64.sha256"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(checksumOf(binary)))),
+		}, nil)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://github.com/apple/pkl/releases/download/0.28.2/pkl-linux-amd64"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(binary)),
+		}, nil)
+
+	memFs := afero.NewMemMapFs()
+	downloader := NewPklDownloader(
+		WithHTTPClient(mockClient),
+		WithFilesystem(memFs),
+		WithRuntime(&mockRuntime{goos: "linux", goarch: "amd64"}),
+		WithReleaseSource(
+			NewHTTPMirrorSource(mockClient, "https://mirror.example.com/pkl"),
+			NewGitHubSource(mockClient),
+		),
+	)
+
+	path := "/tmp/pkl"
+	require.NoError(t, downloader.DownloadVersion("0.28.2", path))
+
+	data, err := afero.ReadFile(memFs, path)
+	require.NoError(t, err)
+	assert.Equal(t, binary, data)
+}
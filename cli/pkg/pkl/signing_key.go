@@ -0,0 +1,11 @@
+package pkl
+
+// appleSigningKey is the ASCII-armored public key used to verify the
+// detached GPG signatures ("<asset>.asc") published alongside Apple's pkl
+// releases. Verification is opt-in via WithSignatureVerification.
+//
+// Apple does not currently publish a dedicated pkl release-signing key, so
+// this is left empty rather than bundling a placeholder that would fail
+// verification silently; WithSignatureVerification(true) requires pairing
+// with WithSigningKey until one is published.
+const appleSigningKey = ""
@@ -0,0 +1,163 @@
+// Package store implements a local, version-aware cache of pkl binaries.
+package store
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Item describes a single pkl binary held in a Store.
+type Item struct {
+	// Version is the pkl release tag, e.g. "0.28.2".
+	Version string
+	// Platform is the "<goos>/<goarch>" pair the binary was built for.
+	Platform string
+	// Path is the on-disk location of the binary.
+	Path string
+}
+
+// Store manages a directory of installed pkl binaries, one subdirectory per
+// version/platform combination, backed by an afero.Fs so it can be tested
+// against an in-memory filesystem.
+type Store struct {
+	fs   afero.Fs
+	root string
+}
+
+// New creates a Store rooted at root. The root directory is created lazily
+// the first time a binary is added.
+func New(fs afero.Fs, root string) *Store {
+	return &Store{fs: fs, root: root}
+}
+
+// Root returns the directory the Store is rooted at.
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Path returns the path a binary for the given version and platform would
+// be installed at, regardless of whether it has actually been added yet.
+func (s *Store) Path(version, platform string) string {
+	return filepath.Join(s.root, dirName(version, platform), binaryName(platform))
+}
+
+// Add installs a pkl binary for the given version and platform, copying
+// reader's contents into the store and marking the result executable. It
+// returns the path the binary was written to.
+func (s *Store) Add(version, platform string, reader io.Reader) (string, error) {
+	path := s.Path(version, platform)
+
+	if err := s.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	out, err := s.fs.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create binary file: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, reader); err != nil {
+		return "", fmt.Errorf("failed to write binary file: %w", err)
+	}
+
+	if err := s.fs.Chmod(path, 0755); err != nil {
+		return "", fmt.Errorf("failed to make binary executable: %w", err)
+	}
+
+	return path, nil
+}
+
+// List returns every item currently held in the store.
+func (s *Store) List() ([]Item, error) {
+	entries, err := afero.ReadDir(s.fs, s.root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read store directory: %w", err)
+	}
+
+	var items []Item
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		version, platform, ok := splitDirName(entry.Name())
+		if !ok {
+			continue
+		}
+
+		items = append(items, Item{
+			Version:  version,
+			Platform: platform,
+			Path:     filepath.Join(s.root, entry.Name(), binaryName(platform)),
+		})
+	}
+
+	return items, nil
+}
+
+// Remove deletes the store entries matching selector, which may be a bare
+// version (removing it for every platform) or a "<version>-<goos>-<goarch>"
+// directory name (removing a single platform). It returns an error if no
+// entry matches.
+func (s *Store) Remove(selector string) error {
+	items, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	var removed bool
+	for _, item := range items {
+		if item.Version != selector && dirName(item.Version, item.Platform) != selector {
+			continue
+		}
+
+		dir := filepath.Join(s.root, dirName(item.Version, item.Platform))
+		if err := s.fs.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", selector, err)
+		}
+		removed = true
+	}
+
+	if !removed {
+		return fmt.Errorf("no installed version matching %q found", selector)
+	}
+
+	return nil
+}
+
+// dirName returns the directory name used to store a given version and
+// platform, e.g. "0.28.2-linux-amd64".
+func dirName(version, platform string) string {
+	return fmt.Sprintf("%s-%s", version, strings.ReplaceAll(platform, "/", "-"))
+}
+
+// splitDirName splits a store directory name back into its version and
+// platform components.
+func splitDirName(name string) (version, platform string, ok bool) {
+	parts := strings.Split(name, "-")
+	if len(parts) < 3 {
+		return "", "", false
+	}
+
+	version = strings.Join(parts[:len(parts)-2], "-")
+	platform = fmt.Sprintf("%s/%s", parts[len(parts)-2], parts[len(parts)-1])
+	return version, platform, true
+}
+
+// binaryName returns the binary name used for a given platform, accounting
+// for the Windows ".exe" suffix.
+func binaryName(platform string) string {
+	if strings.HasPrefix(platform, "windows/") {
+		return "pkl.exe"
+	}
+	return "pkl"
+}
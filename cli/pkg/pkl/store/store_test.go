@@ -0,0 +1,86 @@
+package store
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_AddAndPath(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := New(fs, "/cache/pbl/pkl")
+
+	path, err := s.Add("0.28.2", "linux/amd64", strings.NewReader("binary-contents"))
+	require.NoError(t, err)
+	assert.Equal(t, s.Path("0.28.2", "linux/amd64"), path)
+
+	data, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+	assert.Equal(t, "binary-contents", string(data))
+
+	info, err := fs.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestStore_AddWindowsBinaryName(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := New(fs, "/cache/pbl/pkl")
+
+	path, err := s.Add("0.28.2", "windows/amd64", strings.NewReader("binary-contents"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(path, "pkl.exe"))
+}
+
+func TestStore_List(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := New(fs, "/cache/pbl/pkl")
+
+	_, err := s.Add("0.28.2", "linux/amd64", strings.NewReader("a"))
+	require.NoError(t, err)
+	_, err = s.Add("0.27.0", "darwin/arm64", strings.NewReader("b"))
+	require.NoError(t, err)
+
+	items, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	versions := []string{items[0].Version, items[1].Version}
+	assert.ElementsMatch(t, []string{"0.28.2", "0.27.0"}, versions)
+}
+
+func TestStore_ListEmpty(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := New(fs, "/cache/pbl/pkl")
+
+	items, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestStore_Remove(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := New(fs, "/cache/pbl/pkl")
+
+	_, err := s.Add("0.28.2", "linux/amd64", strings.NewReader("a"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Remove("0.28.2"))
+
+	items, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestStore_RemoveNotFound(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := New(fs, "/cache/pbl/pkl")
+
+	err := s.Remove("0.28.2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no installed version matching")
+}
@@ -0,0 +1,116 @@
+package pkl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// defaultVersionCacheTTL is how long a cached "latest" version lookup is
+// considered fresh before getLatestPklVersion hits the network again.
+const defaultVersionCacheTTL = 24 * time.Hour
+
+// pklRepoKey identifies the pkl release in the version cache file, keyed by
+// "<owner>/<repo>" so the same cache file could hold entries for other
+// release sources in the future.
+const pklRepoKey = "apple/pkl"
+
+// versionCacheEntry is a single cached "latest" version lookup.
+type versionCacheEntry struct {
+	Version   string    `json:"version"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// WithVersionCacheTTL overrides how long a cached "latest" version lookup
+// is considered fresh, instead of the default of 24 hours.
+func WithVersionCacheTTL(ttl time.Duration) Option {
+	return func(d *PklDownloader) {
+		d.cacheTTL = ttl
+	}
+}
+
+// WithVersionCache enables an on-disk cache for "latest" version lookups,
+// backed by fs and stored at path. Without this option, getLatestPklVersion
+// always hits the network.
+func WithVersionCache(fs afero.Fs, path string) Option {
+	return func(d *PklDownloader) {
+		d.cacheFs = fs
+		d.cachePath = path
+	}
+}
+
+// DefaultVersionCachePath returns the OS-appropriate path pbl uses to cache
+// resolved "latest" release versions, e.g. "~/.cache/pbl/pkl-versions.json".
+func DefaultVersionCachePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+
+	return filepath.Join(cacheDir, "pbl", "pkl-versions.json"), nil
+}
+
+// readVersionCache returns the cached "latest" version, if the cache is
+// configured and holds an entry younger than cacheTTL.
+func (d *PklDownloader) readVersionCache() (string, bool) {
+	if d.cacheFs == nil || d.cachePath == "" {
+		return "", false
+	}
+
+	entries, err := d.loadVersionCacheEntries()
+	if err != nil {
+		return "", false
+	}
+
+	entry, ok := entries[pklRepoKey]
+	if !ok || time.Since(entry.FetchedAt) > d.cacheTTL {
+		return "", false
+	}
+
+	return entry.Version, true
+}
+
+// writeVersionCache records version as the latest known release, fetched
+// now. Failures are non-fatal: the cache is a best-effort optimization.
+func (d *PklDownloader) writeVersionCache(version string) {
+	if d.cacheFs == nil || d.cachePath == "" {
+		return
+	}
+
+	entries, err := d.loadVersionCacheEntries()
+	if err != nil {
+		entries = map[string]versionCacheEntry{}
+	}
+
+	entries[pklRepoKey] = versionCacheEntry{Version: version, FetchedAt: time.Now()}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+
+	if dir := filepath.Dir(d.cachePath); dir != "." {
+		_ = d.cacheFs.MkdirAll(dir, 0755)
+	}
+
+	_ = afero.WriteFile(d.cacheFs, d.cachePath, data, 0644)
+}
+
+// loadVersionCacheEntries reads and parses the version cache file.
+func (d *PklDownloader) loadVersionCacheEntries() (map[string]versionCacheEntry, error) {
+	data, err := afero.ReadFile(d.cacheFs, d.cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]versionCacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,126 @@
+package pkl
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPklDownloader_VersionCache_Fresh(t *testing.T) {
+	cacheFs := afero.NewMemMapFs()
+	cachePath := "/cache/pbl/pkl-versions.json"
+
+	writeVersionCacheFixture(t, cacheFs, cachePath, "0.28.2", time.Now())
+
+	// No expectations set: a fresh cache must not make any HTTP calls.
+	mockClient := NewMockHTTPClient(t)
+
+	downloader := NewPklDownloader(
+		WithHTTPClient(mockClient),
+		WithVersionCache(cacheFs, cachePath),
+	)
+
+	version, err := downloader.getLatestPklVersion()
+	require.NoError(t, err)
+	require.Equal(t, "0.28.2", version)
+}
+
+func TestPklDownloader_VersionCache_Expired(t *testing.T) {
+	cacheFs := afero.NewMemMapFs()
+	cachePath := "/cache/pbl/pkl-versions.json"
+
+	writeVersionCacheFixture(t, cacheFs, cachePath, "0.28.1", time.Now().Add(-48*time.Hour))
+
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://api.github.com/repos/apple/pkl/releases/latest"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"tag_name": "0.28.2"}`))),
+		}, nil)
+
+	downloader := NewPklDownloader(
+		WithHTTPClient(mockClient),
+		WithVersionCache(cacheFs, cachePath),
+		WithVersionCacheTTL(24*time.Hour),
+	)
+
+	version, err := downloader.getLatestPklVersion()
+	require.NoError(t, err)
+	require.Equal(t, "0.28.2", version)
+
+	cached, ok := downloader.readVersionCache()
+	require.True(t, ok)
+	require.Equal(t, "0.28.2", cached)
+}
+
+func TestPklDownloader_RefreshLatestVersion_BypassesCache(t *testing.T) {
+	cacheFs := afero.NewMemMapFs()
+	cachePath := "/cache/pbl/pkl-versions.json"
+
+	writeVersionCacheFixture(t, cacheFs, cachePath, "0.28.1", time.Now())
+
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://api.github.com/repos/apple/pkl/releases/latest"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"tag_name": "0.28.2"}`))),
+		}, nil)
+
+	downloader := NewPklDownloader(
+		WithHTTPClient(mockClient),
+		WithVersionCache(cacheFs, cachePath),
+	)
+
+	version, err := downloader.RefreshLatestVersion()
+	require.NoError(t, err)
+	require.Equal(t, "0.28.2", version)
+
+	cached, ok := downloader.readVersionCache()
+	require.True(t, ok)
+	require.Equal(t, "0.28.2", cached)
+}
+
+func TestPklDownloader_NoVersionCache_AlwaysFetches(t *testing.T) {
+	mockClient := NewMockHTTPClient(t)
+	mockClient.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == "https://api.github.com/repos/apple/pkl/releases/latest"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"tag_name": "0.28.2"}`))),
+		}, nil)
+
+	downloader := NewPklDownloader(WithHTTPClient(mockClient))
+
+	version, err := downloader.getLatestPklVersion()
+	require.NoError(t, err)
+	require.Equal(t, "0.28.2", version)
+}
+
+// writeVersionCacheFixture seeds cachePath with a single cache entry for
+// the pkl repo, fetched at fetchedAt.
+func writeVersionCacheFixture(t *testing.T, fs afero.Fs, cachePath, version string, fetchedAt time.Time) {
+	t.Helper()
+
+	entries := map[string]versionCacheEntry{
+		pklRepoKey: {Version: version, FetchedAt: fetchedAt},
+	}
+
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, cachePath, data, 0644))
+}
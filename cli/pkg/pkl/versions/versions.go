@@ -0,0 +1,152 @@
+// Package versions implements parsing and resolution of pkl version
+// selectors such as "0.28.2", "0.28.x", "~0.28", and "latest".
+package versions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Selector is a parsed version constraint. Unconstrained components match
+// any value, e.g. the minor and patch components of "0.x.x".
+type Selector struct {
+	raw    string
+	latest bool
+
+	major, minor, patch             int
+	majorWild, minorWild, patchWild bool
+}
+
+// Parse parses a version selector string. Supported forms are an exact
+// version ("0.28.2"), a wildcard suffix ("0.28.x"), a tilde range that
+// pins the major and minor components ("~0.28"), and "latest".
+func Parse(selector string) (Selector, error) {
+	raw := strings.TrimSpace(selector)
+	if raw == "" {
+		return Selector{}, fmt.Errorf("empty version selector")
+	}
+
+	if strings.EqualFold(raw, "latest") {
+		return Selector{raw: raw, latest: true, majorWild: true, minorWild: true, patchWild: true}, nil
+	}
+
+	trimmed := strings.TrimPrefix(raw, "~")
+	trimmed = strings.TrimPrefix(trimmed, "v")
+	parts := strings.Split(trimmed, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return Selector{}, fmt.Errorf("invalid version selector %q", selector)
+	}
+
+	s := Selector{raw: raw}
+	components := [3]*int{&s.major, &s.minor, &s.patch}
+	wildcards := [3]*bool{&s.majorWild, &s.minorWild, &s.patchWild}
+
+	for i, part := range parts {
+		if part == "x" || part == "*" {
+			*wildcards[i] = true
+			continue
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Selector{}, fmt.Errorf("invalid version selector %q: %w", selector, err)
+		}
+		*components[i] = n
+	}
+
+	// A tilde range or a bare selector shorter than "X.Y.Z" leaves the
+	// missing trailing components unconstrained, e.g. "~0.28" matches any
+	// 0.28.x patch release and "0" matches any 0.x.x release.
+	for i := len(parts); i < 3; i++ {
+		*wildcards[i] = true
+	}
+
+	return s, nil
+}
+
+// Matches reports whether version (a release tag such as "0.28.2" or
+// "v0.28.2") satisfies the selector.
+func (s Selector) Matches(version string) bool {
+	if s.latest {
+		return true
+	}
+
+	major, minor, patch, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+
+	if !s.majorWild && major != s.major {
+		return false
+	}
+	if !s.minorWild && minor != s.minor {
+		return false
+	}
+	if !s.patchWild && patch != s.patch {
+		return false
+	}
+
+	return true
+}
+
+// Resolve returns the highest version in candidates that satisfies the
+// selector.
+func (s Selector) Resolve(candidates []string) (string, error) {
+	var matches []string
+	for _, c := range candidates {
+		if s.Matches(c) {
+			matches = append(matches, c)
+		}
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no version matching %q found", s.raw)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return lessVersion(matches[i], matches[j])
+	})
+
+	return matches[len(matches)-1], nil
+}
+
+// parseVersion extracts the major, minor, and patch components from a
+// release tag such as "0.28.2" or "v0.28.2".
+func parseVersion(version string) (major, minor, patch int, ok bool) {
+	trimmed := strings.TrimPrefix(version, "v")
+	parts := strings.SplitN(trimmed, ".", 3)
+	if len(parts) != 3 {
+		return 0, 0, 0, false
+	}
+
+	values := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		values[i] = n
+	}
+
+	return values[0], values[1], values[2], true
+}
+
+// lessVersion reports whether a sorts before b in ascending semver order.
+// Values that fail to parse fall back to a lexical comparison.
+func lessVersion(a, b string) bool {
+	aMajor, aMinor, aPatch, aOK := parseVersion(a)
+	bMajor, bMinor, bPatch, bOK := parseVersion(b)
+	if !aOK || !bOK {
+		return a < b
+	}
+
+	if aMajor != bMajor {
+		return aMajor < bMajor
+	}
+	if aMinor != bMinor {
+		return aMinor < bMinor
+	}
+	return aPatch < bPatch
+}
@@ -0,0 +1,74 @@
+package versions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse("")
+	require.Error(t, err)
+
+	_, err = Parse("0.28.2.1")
+	require.Error(t, err)
+
+	_, err = Parse("a.b.c")
+	require.Error(t, err)
+}
+
+func TestSelector_Matches(t *testing.T) {
+	cases := []struct {
+		selector string
+		version  string
+		matches  bool
+	}{
+		{"0.28.2", "0.28.2", true},
+		{"0.28.2", "0.28.3", false},
+		{"0.28.x", "0.28.9", true},
+		{"0.28.x", "0.29.0", false},
+		{"~0.28", "0.28.9", true},
+		{"~0.28", "0.29.0", false},
+		{"0.28", "0.28.9", true},
+		{"latest", "0.1.0", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.selector+"/"+tc.version, func(t *testing.T) {
+			sel, err := Parse(tc.selector)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matches, sel.Matches(tc.version))
+		})
+	}
+}
+
+func TestSelector_Resolve(t *testing.T) {
+	candidates := []string{"0.27.0", "0.28.0", "0.28.2", "0.29.0"}
+
+	sel, err := Parse("0.28.x")
+	require.NoError(t, err)
+
+	version, err := sel.Resolve(candidates)
+	require.NoError(t, err)
+	assert.Equal(t, "0.28.2", version)
+}
+
+func TestSelector_ResolveLatest(t *testing.T) {
+	candidates := []string{"0.27.0", "0.28.2", "0.29.0"}
+
+	sel, err := Parse("latest")
+	require.NoError(t, err)
+
+	version, err := sel.Resolve(candidates)
+	require.NoError(t, err)
+	assert.Equal(t, "0.29.0", version)
+}
+
+func TestSelector_ResolveNoMatch(t *testing.T) {
+	sel, err := Parse("1.0.0")
+	require.NoError(t, err)
+
+	_, err = sel.Resolve([]string{"0.28.2"})
+	require.Error(t, err)
+}